@@ -0,0 +1,87 @@
+/******************************************************
+# DESC    : client-level functional options
+# AUTHOR  : Alex Stocks
+# VERSION : 1.0
+# LICENCE : Apache Licence 2.0
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2016-07-04 12:05
+# FILE    : options.go
+******************************************************/
+
+package client
+
+import (
+	"time"
+)
+
+import (
+	"github.com/AlexStocks/dubbogo/codec"
+)
+
+// Options holds the configurable pieces of a pooled rpc call. Option funcs
+// only populate this struct; applyOptions is what actually carries out
+// their side effects (resizing the shared pool, registering codecs), so
+// constructing an Option never mutates process-wide state on its own.
+type Options struct {
+	ContentType string
+	PoolSize    *int
+	PoolTTL     *time.Duration
+
+	codecOverrides map[string]codec.NewCodec
+}
+
+// Option configures Options.
+type Option func(*Options)
+
+// WithCodec overrides the codec used for contentType on this call and
+// registers newCodec for it on the process-wide codec registry. Like
+// WithPoolSize/WithPoolTTL, the registration happens when the Option is
+// applied by applyOptions, not when WithCodec is called.
+func WithCodec(contentType string, newCodec codec.NewCodec) Option {
+	return func(o *Options) {
+		o.ContentType = contentType
+		if o.codecOverrides == nil {
+			o.codecOverrides = make(map[string]codec.NewCodec)
+		}
+		o.codecOverrides[contentType] = newCodec
+	}
+}
+
+// WithPoolSize sets the number of idle connections kept per address in the
+// shared connection pool. It only takes effect once applyOptions applies
+// it, so constructing the Option has no side effect on its own.
+func WithPoolSize(size int) Option {
+	return func(o *Options) {
+		o.PoolSize = &size
+	}
+}
+
+// WithPoolTTL sets how long an idle connection may sit in the shared
+// connection pool before it is closed instead of reused. See WithPoolSize
+// for why this only takes effect once applied.
+func WithPoolTTL(ttl time.Duration) Option {
+	return func(o *Options) {
+		o.PoolTTL = &ttl
+	}
+}
+
+// applyOptions builds an Options from opts and carries out their side
+// effects — registering any codec overrides and resizing the shared
+// connection pool — returning the resulting Options so callers can read
+// back ContentType.
+func applyOptions(opts ...Option) Options {
+	var o Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	for contentType, newCodec := range o.codecOverrides {
+		RegisterCodec(contentType, newCodec)
+	}
+	if o.PoolSize != nil {
+		defaultConnPool.setSize(*o.PoolSize)
+	}
+	if o.PoolTTL != nil {
+		defaultConnPool.setTTL(*o.PoolTTL)
+	}
+	return o
+}
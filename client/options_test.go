@@ -0,0 +1,53 @@
+package client
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/AlexStocks/dubbogo/codec"
+)
+
+func TestWithPoolSizeAndTTLConfigureDefaultPoolOnApply(t *testing.T) {
+	origSize, origTTL := defaultConnPool.size, defaultConnPool.ttl
+	defer func() {
+		defaultConnPool.setSize(origSize)
+		defaultConnPool.setTTL(origTTL)
+	}()
+
+	sizeOpt := WithPoolSize(3)
+	ttlOpt := WithPoolTTL(5 * time.Second)
+	if defaultConnPool.size == 3 || defaultConnPool.ttl == 5*time.Second {
+		t.Fatal("constructing an Option must not mutate defaultConnPool before it is applied")
+	}
+
+	applyOptions(sizeOpt, ttlOpt)
+
+	if defaultConnPool.size != 3 {
+		t.Errorf("defaultConnPool.size = %d, want 3", defaultConnPool.size)
+	}
+	if defaultConnPool.ttl != 5*time.Second {
+		t.Errorf("defaultConnPool.ttl = %v, want 5s", defaultConnPool.ttl)
+	}
+}
+
+func TestWithCodecRegistersOnApplyNotOnConstruction(t *testing.T) {
+	const contentType = "application/x-options-test"
+
+	opt := WithCodec(contentType, func(rwc io.ReadWriteCloser) codec.Codec {
+		return nopCodec{rwc}
+	})
+	if _, err := getCodec(contentType); err == nil {
+		t.Fatal("WithCodec must not register its codec until the Option is applied")
+	}
+
+	o := applyOptions(opt)
+	if o.ContentType != contentType {
+		t.Errorf("applyOptions().ContentType = %q, want %q", o.ContentType, contentType)
+	}
+	if _, err := getCodec(contentType); err != nil {
+		t.Fatalf("getCodec(%q) after applying WithCodec: %v", contentType, err)
+	}
+}
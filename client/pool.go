@@ -0,0 +1,123 @@
+/******************************************************
+# DESC    : bounded per-address pool of idle transport.Client
+#			connections, so repeated rpc calls to the same address
+#			reuse a connection instead of dialing one per call
+# AUTHOR  : Alex Stocks
+# VERSION : 1.0
+# LICENCE : Apache Licence 2.0
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2016-07-25 16:40
+# FILE    : pool.go
+******************************************************/
+
+package client
+
+import (
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/AlexStocks/dubbogo/transport"
+)
+
+const (
+	// DefaultPoolSize is the number of idle connections kept per address.
+	DefaultPoolSize = 10
+	// DefaultPoolTTL is how long an idle connection may sit in the pool
+	// before it is considered stale and closed instead of reused.
+	DefaultPoolTTL = time.Minute
+)
+
+// poolConn is an idle transport.Client tagged with the address it was
+// dialed for and the time it was dialed or last put back into its pool.
+type poolConn struct {
+	transport.Client
+
+	addr  string
+	stamp time.Time
+}
+
+// pool is a bounded, per-address set of idle transport.Client connections.
+type pool struct {
+	sync.Mutex
+
+	size int
+	ttl  time.Duration
+	conn map[string][]*poolConn
+}
+
+func newPool(size int, ttl time.Duration) *pool {
+	return &pool{
+		size: size,
+		ttl:  ttl,
+		conn: make(map[string][]*poolConn),
+	}
+}
+
+// defaultConnPool is the pool used by calls that don't build their own.
+var defaultConnPool = newPool(DefaultPoolSize, DefaultPoolTTL)
+
+// setSize changes the number of idle connections kept per address.
+func (p *pool) setSize(size int) {
+	p.Lock()
+	defer p.Unlock()
+	p.size = size
+}
+
+// setTTL changes how long an idle connection may live before eviction.
+func (p *pool) setTTL(ttl time.Duration) {
+	p.Lock()
+	defer p.Unlock()
+	p.ttl = ttl
+}
+
+// getConn returns an idle connection for addr if one is available and has
+// not exceeded the pool's TTL, dialing a new one via tr otherwise.
+func (p *pool) getConn(addr string, tr transport.Transport, opts ...transport.DialOption) (*poolConn, error) {
+	p.Lock()
+	conns := p.conn[addr]
+	for len(conns) > 0 {
+		conn := conns[len(conns)-1]
+		conns = conns[:len(conns)-1]
+		p.conn[addr] = conns
+
+		if time.Since(conn.stamp) > p.ttl {
+			conn.Client.Close()
+			continue
+		}
+		p.Unlock()
+		return conn, nil
+	}
+	p.Unlock()
+
+	c, err := tr.Dial(addr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &poolConn{Client: c, addr: addr, stamp: time.Now()}, nil
+}
+
+// release returns conn to its pool for reuse, unless err is non-nil, the
+// pool for its address is already full, or more than the pool's TTL has
+// passed since conn was dialed or last put back into the pool — in any of
+// those cases conn is closed instead.
+func (p *pool) release(conn *poolConn, err error) {
+	if err != nil {
+		conn.Client.Close()
+		return
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	if time.Since(conn.stamp) > p.ttl {
+		conn.Client.Close()
+		return
+	}
+	if len(p.conn[conn.addr]) >= p.size {
+		conn.Client.Close()
+		return
+	}
+	conn.stamp = time.Now()
+	p.conn[conn.addr] = append(p.conn[conn.addr], conn)
+}
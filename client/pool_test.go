@@ -0,0 +1,185 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/AlexStocks/dubbogo/transport"
+)
+
+type countingTransportClient struct {
+	closed bool
+}
+
+func (c *countingTransportClient) Send(*transport.Message) error { return nil }
+func (c *countingTransportClient) Recv(*transport.Message) error { return nil }
+func (c *countingTransportClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+type countingTransport struct {
+	dials int
+}
+
+func (t *countingTransport) Dial(addr string, opts ...transport.DialOption) (transport.Client, error) {
+	t.dials++
+	return &countingTransportClient{}, nil
+}
+
+func TestPoolReusesConnectionOnRelease(t *testing.T) {
+	p := newPool(2, time.Minute)
+	tr := &countingTransport{}
+
+	conn, err := p.getConn("127.0.0.1:8080", tr)
+	if err != nil {
+		t.Fatalf("getConn() error = %v", err)
+	}
+	p.release(conn, nil)
+
+	conn2, err := p.getConn("127.0.0.1:8080", tr)
+	if err != nil {
+		t.Fatalf("getConn() error = %v", err)
+	}
+	if conn2 != conn {
+		t.Fatal("getConn() after release should return the same pooled connection")
+	}
+	if tr.dials != 1 {
+		t.Fatalf("dials = %d, want 1", tr.dials)
+	}
+}
+
+func TestPoolClosesConnOnReleaseError(t *testing.T) {
+	p := newPool(2, time.Minute)
+	tr := &countingTransport{}
+
+	conn, err := p.getConn("127.0.0.1:8080", tr)
+	if err != nil {
+		t.Fatalf("getConn() error = %v", err)
+	}
+	p.release(conn, errShutdown)
+
+	if !conn.Client.(*countingTransportClient).closed {
+		t.Fatal("release() with non-nil error should close the connection")
+	}
+
+	conn2, err := p.getConn("127.0.0.1:8080", tr)
+	if err != nil {
+		t.Fatalf("getConn() error = %v", err)
+	}
+	if conn2 == conn {
+		t.Fatal("getConn() should not reuse a connection that was released with an error")
+	}
+	if tr.dials != 2 {
+		t.Fatalf("dials = %d, want 2", tr.dials)
+	}
+}
+
+func TestPoolEvictsExpiredConnection(t *testing.T) {
+	p := newPool(2, time.Millisecond)
+	tr := &countingTransport{}
+
+	conn, err := p.getConn("127.0.0.1:8080", tr)
+	if err != nil {
+		t.Fatalf("getConn() error = %v", err)
+	}
+	p.release(conn, nil)
+
+	time.Sleep(5 * time.Millisecond)
+
+	conn2, err := p.getConn("127.0.0.1:8080", tr)
+	if err != nil {
+		t.Fatalf("getConn() error = %v", err)
+	}
+	if conn2 == conn {
+		t.Fatal("getConn() should not return a TTL-expired connection")
+	}
+	if !conn.Client.(*countingTransportClient).closed {
+		t.Fatal("expired connection should have been closed")
+	}
+}
+
+// TestNewPooledRpcPlusCodecReusesConnectionOnClose exercises pool reuse
+// through the actual rpcPlusCodec wiring (newPooledRpcPlusCodec and
+// Close()) rather than calling pool.getConn/release directly, so it
+// covers the path a real call takes.
+func TestNewPooledRpcPlusCodecReusesConnectionOnClose(t *testing.T) {
+	p := newPool(2, time.Minute)
+	tr := &countingTransport{}
+
+	req1 := &transport.Message{Header: map[string]string{}}
+	c1, err := newPooledRpcPlusCodec(req1, tr, "127.0.0.1:8080", p, nil)
+	if err != nil {
+		t.Fatalf("newPooledRpcPlusCodec() error = %v", err)
+	}
+	conn1 := c1.conn
+	if err := c1.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	req2 := &transport.Message{Header: map[string]string{}}
+	c2, err := newPooledRpcPlusCodec(req2, tr, "127.0.0.1:8080", p, nil)
+	if err != nil {
+		t.Fatalf("newPooledRpcPlusCodec() error = %v", err)
+	}
+	defer c2.Close()
+
+	if c2.conn != conn1 {
+		t.Fatal("newPooledRpcPlusCodec() after Close() should reuse the pooled connection")
+	}
+	if tr.dials != 1 {
+		t.Fatalf("dials = %d, want 1", tr.dials)
+	}
+}
+
+// TestNewPooledRpcPlusCodecEvictsConnectionAfterSendError checks that a
+// codec which recorded a transport error via sawErr has its connection
+// evicted by Close() instead of being pooled for reuse.
+func TestNewPooledRpcPlusCodecEvictsConnectionAfterSendError(t *testing.T) {
+	p := newPool(2, time.Minute)
+	tr := &countingTransport{}
+
+	req := &transport.Message{Header: map[string]string{}}
+	c, err := newPooledRpcPlusCodec(req, tr, "127.0.0.1:8080", p, nil)
+	if err != nil {
+		t.Fatalf("newPooledRpcPlusCodec() error = %v", err)
+	}
+	conn := c.conn
+	c.sawErr = errShutdown
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if !conn.Client.(*countingTransportClient).closed {
+		t.Fatal("Close() after sawErr should close rather than pool the connection")
+	}
+
+	req2 := &transport.Message{Header: map[string]string{}}
+	c2, err := newPooledRpcPlusCodec(req2, tr, "127.0.0.1:8080", p, nil)
+	if err != nil {
+		t.Fatalf("newPooledRpcPlusCodec() error = %v", err)
+	}
+	defer c2.Close()
+	if c2.conn == conn {
+		t.Fatal("newPooledRpcPlusCodec() should not reuse a connection evicted after sawErr")
+	}
+	if tr.dials != 2 {
+		t.Fatalf("dials = %d, want 2", tr.dials)
+	}
+}
+
+func TestPoolClosesConnWhenFull(t *testing.T) {
+	p := newPool(1, time.Minute)
+	tr := &countingTransport{}
+
+	a, _ := p.getConn("addr", tr)
+	b, _ := p.getConn("addr", tr)
+
+	p.release(a, nil)
+	p.release(b, nil)
+
+	if !b.Client.(*countingTransportClient).closed {
+		t.Fatal("release() beyond pool size should close the connection")
+	}
+}
@@ -0,0 +1,93 @@
+/******************************************************
+# DESC    : public Request/Response views of an rpc call, and the Router
+#			interface for plugging in custom routing (retries, hedging,
+#			traffic shadowing, ...) without forking the rpc client
+# AUTHOR  : Alex Stocks
+# VERSION : 1.0
+# LICENCE : Apache Licence 2.0
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2016-08-01 10:15
+# FILE    : request.go
+******************************************************/
+
+package client
+
+import (
+	"context"
+)
+
+import (
+	"github.com/AlexStocks/dubbogo/codec"
+)
+
+// Request is the outgoing half of an rpc call. Middleware can read Body
+// or rewrite it through Codec before the call hits the wire.
+type Request interface {
+	Service() string
+	Method() string
+	ContentType() string
+	Body() interface{}
+	Codec() codec.Writer
+	Stream() bool
+}
+
+// Response is the result of an rpc call. Read gives middleware raw access
+// to the still-undecoded response body, while Codec lets it decode the
+// body itself rather than going through ReadResponseBody.
+type Response interface {
+	Codec() codec.Reader
+	Header() map[string]string
+	Read() ([]byte, error)
+}
+
+// Router sends a Request and returns its Response. Implementing Router is
+// the extension point for middleware such as retries, hedged requests, or
+// traffic shadowing, without having to fork the rpc client.
+type Router interface {
+	SendRequest(ctx context.Context, req Request) (Response, error)
+}
+
+// rpcRequest is the Request view of an rpcPlusCodec's most recent
+// WriteRequest call; it shares the codec rather than copying it. That
+// WriteRequest call has already sent the request, so a write through
+// Codec() is not part of it.
+type rpcRequest struct {
+	codec *rpcPlusCodec
+}
+
+func (r *rpcRequest) Service() string     { return r.codec.lastService }
+func (r *rpcRequest) Method() string      { return r.codec.lastMethod }
+func (r *rpcRequest) Body() interface{}   { return r.codec.lastBody }
+func (r *rpcRequest) Stream() bool        { return r.codec.lastStream }
+func (r *rpcRequest) Codec() codec.Writer { return r.codec.codec }
+
+func (r *rpcRequest) ContentType() string {
+	if ct := r.codec.req.Header["Content-Type"]; ct != "" {
+		return ct
+	}
+	return defaultContentType
+}
+
+// rpcResponse is the Response view of an rpcPlusCodec's most recently
+// read response.
+type rpcResponse struct {
+	codec *rpcPlusCodec
+}
+
+func (r *rpcResponse) Codec() codec.Reader       { return r.codec.codec }
+func (r *rpcResponse) Header() map[string]string { return r.codec.respHeader }
+
+func (r *rpcResponse) Read() ([]byte, error) {
+	return r.codec.rawBody, nil
+}
+
+var (
+	_ Request  = (*rpcRequest)(nil)
+	_ Response = (*rpcResponse)(nil)
+)
+
+// AsRequest returns the Request view of c's most recent WriteRequest call.
+func (c *rpcPlusCodec) AsRequest() Request { return &rpcRequest{codec: c} }
+
+// AsResponse returns the Response view of c's most recently read response.
+func (c *rpcPlusCodec) AsResponse() Response { return &rpcResponse{codec: c} }
@@ -15,11 +15,19 @@ package client
 import (
 	"bytes"
 	"errors"
+	"fmt"
+	"io"
+	"sync"
 )
 
 import (
 	"github.com/AlexStocks/dubbogo/codec"
+	rawbytes "github.com/AlexStocks/dubbogo/codec/bytes"
+	"github.com/AlexStocks/dubbogo/codec/grpc"
 	"github.com/AlexStocks/dubbogo/codec/jsonrpc"
+	"github.com/AlexStocks/dubbogo/codec/proto"
+	"github.com/AlexStocks/dubbogo/codec/protorpc"
+	dubbogoErrors "github.com/AlexStocks/dubbogo/errors"
 	"github.com/AlexStocks/dubbogo/transport"
 )
 
@@ -46,6 +54,32 @@ type rpcPlusCodec struct {
 
 	req *transport.Message
 	buf *readWriteCloser
+
+	// pool and conn are set when client was obtained from a pool, so
+	// Close can release rather than unconditionally close it.
+	pool *pool
+	conn *poolConn
+
+	// sawErr is set the first time a transport Send/Recv call fails, so
+	// Close knows conn should be evicted rather than returned to pool.
+	sawErr error
+
+	// lastService, lastMethod, lastBody and lastStream cache the fields of
+	// the most recent WriteRequest call, and respHeader the most recent
+	// ReadResponseHeader call, so rpcRequest/rpcResponse (request.go) can
+	// present them as the public Request/Response views without copying.
+	lastService string
+	lastMethod  string
+	lastBody    interface{}
+	lastStream  bool
+	respHeader  map[string]string
+
+	// rawBody is the most recent response's raw, still-undecoded wire
+	// bytes, captured in ReadResponseHeader before the bufio.Reader
+	// wrapping buf.rbuf drains them; by the time ReadHeader returns,
+	// rbuf itself is already empty, so Response.Read (request.go) reads
+	// from here instead.
+	rawBody []byte
 }
 
 type readWriteCloser struct {
@@ -58,6 +92,10 @@ type clientCodec interface {
 	ReadResponseHeader(*response) error
 	ReadResponseBody(interface{}) error
 
+	// CloseSend writes a sentinel EOS message marking the send side of a
+	// stream as done; it is a no-op for plain request/response calls.
+	CloseSend(*request) error
+
 	Close() error
 }
 
@@ -65,6 +103,7 @@ type request struct {
 	Service       string
 	ServiceMethod string // format: "Service.Method"
 	Seq           uint64 // sequence number chosen by client
+	Stream        bool   // true once this call has been upgraded to a bidirectional stream
 	// next          *request // for free list in Server
 }
 
@@ -78,12 +117,38 @@ type response struct {
 var (
 	defaultContentType = "application/octet-stream"
 
+	codecMu       sync.RWMutex
 	defaultCodecs = map[string]codec.NewCodec{
-		"application/json":    jsonrpc.NewCodec,
-		"application/jsonrpc": jsonrpc.NewCodec,
+		"application/json":         jsonrpc.NewCodec,
+		"application/jsonrpc":      jsonrpc.NewCodec,
+		"application/protobuf":     proto.NewCodec,
+		"application/proto-rpc":    protorpc.NewCodec,
+		"application/grpc":         grpc.NewCodec,
+		"application/grpc+proto":   grpc.NewCodec,
+		"application/grpc+json":    grpc.NewJSONCodec,
+		"application/octet-stream": rawbytes.NewCodec,
 	}
 )
 
+// RegisterCodec adds or overrides the codec.NewCodec used for contentType.
+// It is safe to call concurrently and is typically done from an init()
+// function or before any client call is made.
+func RegisterCodec(contentType string, newCodec codec.NewCodec) {
+	codecMu.Lock()
+	defer codecMu.Unlock()
+	defaultCodecs[contentType] = newCodec
+}
+
+// getCodec resolves the codec.NewCodec registered for contentType.
+func getCodec(contentType string) (codec.NewCodec, error) {
+	codecMu.RLock()
+	defer codecMu.RUnlock()
+	if c, ok := defaultCodecs[contentType]; ok {
+		return c, nil
+	}
+	return nil, fmt.Errorf("unsupported content-type: %s", contentType)
+}
+
 func (rwc *readWriteCloser) Read(p []byte) (n int, err error) {
 	return rwc.rbuf.Read(p)
 }
@@ -98,7 +163,16 @@ func (rwc *readWriteCloser) Close() error {
 	return nil
 }
 
-func newRpcPlusCodec(req *transport.Message, client transport.Client, c codec.NewCodec) *rpcPlusCodec {
+func newRpcPlusCodec(req *transport.Message, client transport.Client) (*rpcPlusCodec, error) {
+	contentType := req.Header["Content-Type"]
+	if contentType == "" {
+		contentType = defaultContentType
+	}
+	newCodec, err := getCodec(contentType)
+	if err != nil {
+		return nil, dubbogoErrors.InternalServerError("go.dubbogo.client", "%v", err)
+	}
+
 	rwc := &readWriteCloser{
 		wbuf: bytes.NewBuffer(nil),
 		rbuf: bytes.NewBuffer(nil),
@@ -106,44 +180,126 @@ func newRpcPlusCodec(req *transport.Message, client transport.Client, c codec.Ne
 	r := &rpcPlusCodec{
 		buf:    rwc,
 		client: client,
-		codec:  c(rwc),
+		codec:  newCodec(rwc),
 		req:    req,
 	}
-	return r
+	return r, nil
+}
+
+// newPooledRpcPlusCodec is like newRpcPlusCodec except the transport.Client
+// is borrowed from p instead of being dialed fresh for every call; Close
+// returns it to p instead of closing it outright. opts are applied before
+// the connection is obtained, so a WithCodec in opts overrides req's
+// Content-Type. A WithPoolSize/WithPoolTTL in opts resizes defaultConnPool
+// rather than p, so it only governs the getConn call below when p is
+// defaultConnPool.
+func newPooledRpcPlusCodec(req *transport.Message, tr transport.Transport, addr string, p *pool, dialOpts []transport.DialOption, opts ...Option) (*rpcPlusCodec, error) {
+	o := applyOptions(opts...)
+	if o.ContentType != "" {
+		req.Header["Content-Type"] = o.ContentType
+	}
+
+	conn, err := p.getConn(addr, tr, dialOpts...)
+	if err != nil {
+		return nil, dubbogoErrors.InternalServerError("go.dubbogo.client", "%v", err)
+	}
+
+	r, err := newRpcPlusCodec(req, conn)
+	if err != nil {
+		p.release(conn, err)
+		return nil, err
+	}
+	r.pool = p
+	r.conn = conn
+	return r, nil
 }
 
 func (c *rpcPlusCodec) WriteRequest(req *request, body interface{}) error {
 	c.buf.wbuf.Reset()
+	c.lastService = req.Service
+	c.lastMethod = req.ServiceMethod
+	c.lastBody = body
+	c.lastStream = req.Stream
+	msgType := codec.Request
+	if req.Stream {
+		msgType = codec.Stream
+	}
 	m := &codec.Message{
 		Id:     req.Seq,
 		Target: req.Service,
 		Method: req.ServiceMethod,
-		Type:   codec.Request,
+		Type:   msgType,
 		Header: map[string]string{},
 	}
 	if err := c.codec.Write(m, body); err != nil {
-		return err
+		return dubbogoErrors.InternalServerError("go.dubbogo.client", "%v", err)
 	}
 	c.req.Body = c.buf.wbuf.Bytes()
 	for k, v := range m.Header {
 		c.req.Header[k] = v
 	}
-	return c.client.Send(c.req)
+	if err := c.client.Send(c.req); err != nil {
+		c.sawErr = err
+		return dubbogoErrors.InternalServerError("go.dubbogo.client", "%v", err)
+	}
+	return nil
+}
+
+// CloseSend marks the send half of a stream as done by writing a message
+// whose Error is the lastStreamResponseError sentinel; ReadResponseHeader
+// on the peer translates that sentinel back into io.EOF.
+func (c *rpcPlusCodec) CloseSend(req *request) error {
+	if !req.Stream {
+		return nil
+	}
+	c.buf.wbuf.Reset()
+	m := &codec.Message{
+		Id:     req.Seq,
+		Target: req.Service,
+		Method: req.ServiceMethod,
+		Type:   codec.Stream,
+		Error:  lastStreamResponseError,
+		Header: map[string]string{},
+	}
+	if err := c.codec.Write(m, nil); err != nil {
+		return dubbogoErrors.InternalServerError("go.dubbogo.client", "%v", err)
+	}
+	c.req.Body = c.buf.wbuf.Bytes()
+	for k, v := range m.Header {
+		c.req.Header[k] = v
+	}
+	if err := c.client.Send(c.req); err != nil {
+		c.sawErr = err
+		return dubbogoErrors.InternalServerError("go.dubbogo.client", "%v", err)
+	}
+	return nil
 }
 
 func (c *rpcPlusCodec) ReadResponseHeader(r *response) error {
 	var m transport.Message
 	if err := c.client.Recv(&m); err != nil {
-		return err
+		c.sawErr = err
+		return dubbogoErrors.InternalServerError("go.dubbogo.client", "%v", err)
 	}
 	c.buf.rbuf.Reset()
 	c.buf.rbuf.Write(m.Body)
+	c.rawBody = m.Body
 	var cm codec.Message
 	err := c.codec.ReadHeader(&cm, codec.Response)
 	r.ServiceMethod = cm.Method
 	r.Seq = cm.Id
 	r.Error = cm.Error
-	return err
+	c.respHeader = cm.Header
+	if err != nil {
+		return dubbogoErrors.InternalServerError("go.dubbogo.client", "%v", err)
+	}
+	if cm.Error == lastStreamResponseError {
+		return io.EOF
+	}
+	if cm.Error != "" {
+		return dubbogoErrors.Parse(cm.Error)
+	}
+	return nil
 }
 
 func (c *rpcPlusCodec) ReadResponseBody(b interface{}) error {
@@ -154,5 +310,9 @@ func (c *rpcPlusCodec) Close() error {
 	// log.Debug("close rpcPlusCodec{%#v}", c)
 	c.buf.Close()
 	c.codec.Close()
+	if c.pool != nil && c.conn != nil {
+		c.pool.release(c.conn, c.sawErr)
+		return nil
+	}
 	return c.client.Close()
 }
@@ -0,0 +1,207 @@
+package client
+
+import (
+	"testing"
+)
+
+import (
+	"io"
+)
+
+import (
+	"bytes"
+)
+
+import (
+	"github.com/AlexStocks/dubbogo/codec"
+	rawbytes "github.com/AlexStocks/dubbogo/codec/bytes"
+	dubbogoErrors "github.com/AlexStocks/dubbogo/errors"
+	"github.com/AlexStocks/dubbogo/transport"
+)
+
+type fakeTransportClient struct {
+	sent []*transport.Message
+	recv []*transport.Message
+}
+
+func (f *fakeTransportClient) Send(m *transport.Message) error {
+	f.sent = append(f.sent, m)
+	if len(f.recv) == 0 {
+		return nil
+	}
+	return nil
+}
+
+func (f *fakeTransportClient) Recv(m *transport.Message) error {
+	if len(f.recv) == 0 {
+		return nil
+	}
+	next := f.recv[0]
+	f.recv = f.recv[1:]
+	*m = *next
+	return nil
+}
+
+func (f *fakeTransportClient) Close() error { return nil }
+
+func TestGetCodecBuiltins(t *testing.T) {
+	for _, ct := range []string{
+		"application/json",
+		"application/jsonrpc",
+		"application/protobuf",
+		"application/proto-rpc",
+		"application/grpc",
+		"application/grpc+proto",
+		"application/grpc+json",
+		"application/octet-stream",
+	} {
+		if _, err := getCodec(ct); err != nil {
+			t.Errorf("getCodec(%q) unexpected error: %v", ct, err)
+		}
+	}
+}
+
+func TestGetCodecUnknownContentType(t *testing.T) {
+	if _, err := getCodec("application/does-not-exist"); err == nil {
+		t.Fatal("getCodec() with unregistered content-type should error")
+	}
+}
+
+type nopCodec struct {
+	io.ReadWriteCloser
+}
+
+func (nopCodec) Write(*codec.Message, interface{}) error            { return nil }
+func (nopCodec) ReadHeader(*codec.Message, codec.MessageType) error { return nil }
+func (nopCodec) ReadBody(interface{}) error                         { return nil }
+func (nopCodec) String() string                                     { return "nop" }
+
+func TestRegisterCodecOverride(t *testing.T) {
+	RegisterCodec("application/x-test", func(rwc io.ReadWriteCloser) codec.Codec {
+		return nopCodec{rwc}
+	})
+	if _, err := getCodec("application/x-test"); err != nil {
+		t.Fatalf("getCodec() after RegisterCodec() error = %v", err)
+	}
+}
+
+func TestNewRpcPlusCodecPicksRegisteredCodec(t *testing.T) {
+	req := &transport.Message{Header: map[string]string{"Content-Type": "application/protobuf"}}
+	c, err := newRpcPlusCodec(req, &fakeTransportClient{})
+	if err != nil {
+		t.Fatalf("newRpcPlusCodec() error = %v", err)
+	}
+	if c.codec == nil {
+		t.Fatal("newRpcPlusCodec() codec is nil")
+	}
+}
+
+func TestNewRpcPlusCodecUnknownContentType(t *testing.T) {
+	req := &transport.Message{Header: map[string]string{"Content-Type": "application/nope"}}
+	_, err := newRpcPlusCodec(req, &fakeTransportClient{})
+	if err == nil {
+		t.Fatal("newRpcPlusCodec() with unknown content-type should error")
+	}
+	if _, ok := err.(*dubbogoErrors.Error); !ok {
+		t.Fatalf("newRpcPlusCodec() error = %T, want *errors.Error", err)
+	}
+}
+
+type nopReadWriteCloser struct {
+	*bytes.Buffer
+}
+
+func (nopReadWriteCloser) Close() error { return nil }
+
+// TestRpcPlusCodecRoundTripsAllBuiltinContentTypes exercises every builtin
+// content-type end to end through newRpcPlusCodec, WriteRequest,
+// ReadResponseHeader and ReadResponseBody, the way a real call over
+// transport.Client would, rather than just checking that a codec resolves.
+func TestRpcPlusCodecRoundTripsAllBuiltinContentTypes(t *testing.T) {
+	for _, ct := range []string{
+		"application/json",
+		"application/jsonrpc",
+		"application/protobuf",
+		"application/proto-rpc",
+		"application/grpc",
+		"application/grpc+proto",
+		"application/grpc+json",
+		"application/octet-stream",
+	} {
+		newCodec, err := getCodec(ct)
+		if err != nil {
+			t.Errorf("getCodec(%q) error = %v", ct, err)
+			continue
+		}
+
+		// Build the response fixture with the same codec newRpcPlusCodec
+		// will pick for ct, so ReadResponseHeader/ReadResponseBody below
+		// exercise a real decode rather than just Write's encode path.
+		respPayload := []byte("response-" + ct)
+		rwc := nopReadWriteCloser{Buffer: bytes.NewBuffer(nil)}
+		if err := newCodec(rwc).Write(&codec.Message{Type: codec.Response}, &respPayload); err != nil {
+			t.Errorf("prepare response fixture for %q: %v", ct, err)
+			continue
+		}
+
+		req := &transport.Message{Header: map[string]string{"Content-Type": ct}}
+		fake := &fakeTransportClient{recv: []*transport.Message{{Body: rwc.Bytes()}}}
+		c, err := newRpcPlusCodec(req, fake)
+		if err != nil {
+			t.Errorf("newRpcPlusCodec(%q) error = %v", ct, err)
+			continue
+		}
+
+		reqPayload := []byte("request-" + ct)
+		if err := c.WriteRequest(&request{Service: "Echo", ServiceMethod: "Echo.Call"}, &reqPayload); err != nil {
+			t.Errorf("WriteRequest(%q) error = %v", ct, err)
+			continue
+		}
+		if len(fake.sent) != 1 {
+			t.Errorf("WriteRequest(%q) sent %d messages, want 1", ct, len(fake.sent))
+			continue
+		}
+
+		var resp response
+		if err := c.ReadResponseHeader(&resp); err != nil {
+			t.Errorf("ReadResponseHeader(%q) error = %v", ct, err)
+			continue
+		}
+
+		var out []byte
+		if err := c.ReadResponseBody(&out); err != nil {
+			t.Errorf("ReadResponseBody(%q) error = %v", ct, err)
+			continue
+		}
+		if !bytes.Equal(out, respPayload) {
+			t.Errorf("ReadResponseBody(%q) = %q, want %q", ct, out, respPayload)
+		}
+	}
+}
+
+func TestReadResponseHeaderParsesStructuredError(t *testing.T) {
+	wireErr := dubbogoErrors.NotFound("go.dubbogo.client", "no such user")
+
+	rwc := nopReadWriteCloser{Buffer: bytes.NewBuffer(nil)}
+	wireCodec := rawbytes.NewCodec(rwc)
+	if err := wireCodec.Write(&codec.Message{Type: codec.Response, Error: wireErr.Error()}, nil); err != nil {
+		t.Fatalf("failed to prepare fixture: %v", err)
+	}
+
+	req := &transport.Message{Header: map[string]string{"Content-Type": "application/octet-stream"}}
+	fake := &fakeTransportClient{recv: []*transport.Message{{Body: rwc.Bytes()}}}
+	c, err := newRpcPlusCodec(req, fake)
+	if err != nil {
+		t.Fatalf("newRpcPlusCodec() error = %v", err)
+	}
+
+	var resp response
+	err = c.ReadResponseHeader(&resp)
+	got, ok := err.(*dubbogoErrors.Error)
+	if !ok {
+		t.Fatalf("ReadResponseHeader() error = %T, want *errors.Error", err)
+	}
+	if got.Code != "404" {
+		t.Fatalf("ReadResponseHeader() error code = %q, want 404", got.Code)
+	}
+}
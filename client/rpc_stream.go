@@ -0,0 +1,118 @@
+/******************************************************
+# DESC    : client-side bidirectional stream built on top of a single
+#			transport.Client connection and its clientCodec
+# AUTHOR  : Alex Stocks
+# VERSION : 1.0
+# LICENCE : Apache Licence 2.0
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2016-07-11 09:20
+# FILE    : rpc_stream.go
+******************************************************/
+
+package client
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// rpcStream is a bidirectional stream of Send/Recv calls multiplexed over
+// the request/response pair of a single rpc call.
+type rpcStream struct {
+	sync.Mutex
+
+	context context.Context
+	codec   clientCodec
+
+	request  *request
+	response *response
+
+	seq uint64
+
+	// sendClosed and closed track the two halves of the stream
+	// independently: CloseSend only shuts down Send, so a still-open Recv
+	// can keep draining whatever the peer has in flight. closed marks the
+	// whole stream done and is only set by Close.
+	sendClosed bool
+	closed     bool
+
+	// sendEOS controls whether CloseSend writes the lastStreamResponseError
+	// sentinel onto the wire; some peers don't expect it, so it can be
+	// turned off for them.
+	sendEOS bool
+}
+
+func newRpcStream(ctx context.Context, codec clientCodec, service, method string, sendEOS bool) *rpcStream {
+	return &rpcStream{
+		context: ctx,
+		codec:   codec,
+		sendEOS: sendEOS,
+		request: &request{
+			Service:       service,
+			ServiceMethod: method,
+			Stream:        true,
+		},
+		response: &response{},
+	}
+}
+
+// Context returns the context the stream was created with.
+func (r *rpcStream) Context() context.Context {
+	return r.context
+}
+
+// Send writes body as the next message on the stream.
+func (r *rpcStream) Send(body interface{}) error {
+	r.Lock()
+	defer r.Unlock()
+	if r.closed || r.sendClosed {
+		return errShutdown
+	}
+	r.seq++
+	r.request.Seq = r.seq
+	return r.codec.WriteRequest(r.request, body)
+}
+
+// Recv reads the next message on the stream into body. It returns io.EOF
+// once the peer has closed its send side via CloseSend.
+func (r *rpcStream) Recv(body interface{}) error {
+	r.Lock()
+	defer r.Unlock()
+	if r.closed {
+		return errShutdown
+	}
+	if err := r.codec.ReadResponseHeader(r.response); err != nil {
+		return err
+	}
+	return r.codec.ReadResponseBody(body)
+}
+
+// CloseSend closes the send half of the stream. When sendEOS is set it
+// writes the lastStreamResponseError sentinel so the peer's Recv observes
+// io.EOF; otherwise the stream is just marked closed locally.
+func (r *rpcStream) CloseSend() error {
+	r.Lock()
+	defer r.Unlock()
+	if r.closed || r.sendClosed {
+		return nil
+	}
+	r.sendClosed = true
+	if !r.sendEOS {
+		return nil
+	}
+	r.seq++
+	r.request.Seq = r.seq
+	return r.codec.CloseSend(r.request)
+}
+
+// Close shuts down both halves of the stream and releases the underlying
+// codec and transport connection.
+func (r *rpcStream) Close() error {
+	r.Lock()
+	defer r.Unlock()
+	r.closed = true
+	return r.codec.Close()
+}
+
+var _ io.Closer = (*rpcStream)(nil)
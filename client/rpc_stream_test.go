@@ -0,0 +1,156 @@
+package client
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+// pipeCodec is a clientCodec backed by channels so two rpcStreams can be
+// wired directly to each other in-process, without a real transport.Client.
+type pipeCodec struct {
+	out  chan pipeMsg
+	in   chan pipeMsg
+	last interface{}
+}
+
+type pipeMsg struct {
+	seq  uint64
+	body interface{}
+	err  string
+}
+
+func (p *pipeCodec) WriteRequest(req *request, body interface{}) error {
+	p.out <- pipeMsg{seq: req.Seq, body: body}
+	return nil
+}
+
+func (p *pipeCodec) CloseSend(req *request) error {
+	p.out <- pipeMsg{seq: req.Seq, err: lastStreamResponseError}
+	return nil
+}
+
+func (p *pipeCodec) ReadResponseHeader(r *response) error {
+	m, ok := <-p.in
+	if !ok {
+		return io.EOF
+	}
+	r.Seq = m.seq
+	r.Error = m.err
+	p.last = m.body
+	if m.err == lastStreamResponseError {
+		return io.EOF
+	}
+	return nil
+}
+
+func (p *pipeCodec) ReadResponseBody(body interface{}) error {
+	if dst, ok := body.(*string); ok {
+		if src, ok := p.last.(string); ok {
+			*dst = src
+		}
+	}
+	return nil
+}
+
+func (p *pipeCodec) Close() error {
+	close(p.out)
+	return nil
+}
+
+func newConnectedStreams(sendEOS bool) (client *rpcStream, server *rpcStream) {
+	clientToServer := make(chan pipeMsg, 1)
+	serverToClient := make(chan pipeMsg, 1)
+
+	clientCodec := &pipeCodec{out: clientToServer, in: serverToClient}
+	serverCodec := &pipeCodec{out: serverToClient, in: clientToServer}
+
+	client = newRpcStream(context.Background(), clientCodec, "Echo", "Echo.Call", sendEOS)
+	server = newRpcStream(context.Background(), serverCodec, "Echo", "Echo.Call", sendEOS)
+	return
+}
+
+func TestRpcStreamEchoMultipleCycles(t *testing.T) {
+	client, server := newConnectedStreams(true)
+
+	for i := 0; i < 3; i++ {
+		want := "ping"
+		if err := client.Send(want); err != nil {
+			t.Fatalf("client.Send() error = %v", err)
+		}
+
+		var got string
+		if err := server.Recv(&got); err != nil {
+			t.Fatalf("server.Recv() error = %v", err)
+		}
+		if got != want {
+			t.Fatalf("server.Recv() = %q, want %q", got, want)
+		}
+
+		if err := server.Send(got); err != nil {
+			t.Fatalf("server.Send() error = %v", err)
+		}
+
+		var echoed string
+		if err := client.Recv(&echoed); err != nil {
+			t.Fatalf("client.Recv() error = %v", err)
+		}
+		if echoed != want {
+			t.Fatalf("client.Recv() = %q, want %q", echoed, want)
+		}
+	}
+
+	if err := client.CloseSend(); err != nil {
+		t.Fatalf("client.CloseSend() error = %v", err)
+	}
+
+	var discard string
+	if err := server.Recv(&discard); err != io.EOF {
+		t.Fatalf("server.Recv() after CloseSend() error = %v, want io.EOF", err)
+	}
+}
+
+func TestRpcStreamCloseSendWithoutEOSIsSilent(t *testing.T) {
+	client, server := newConnectedStreams(false)
+
+	if err := client.CloseSend(); err != nil {
+		t.Fatalf("client.CloseSend() error = %v", err)
+	}
+	if err := client.Send("too-late"); err != errShutdown {
+		t.Fatalf("Send() after CloseSend() error = %v, want errShutdown", err)
+	}
+
+	// the peer never receives an EOS sentinel because sendEOS is false
+	select {
+	case <-server.codec.(*pipeCodec).in:
+		t.Fatal("server should not have received a message")
+	default:
+	}
+}
+
+func TestRpcStreamCloseSendDoesNotShutDownRecv(t *testing.T) {
+	client, server := newConnectedStreams(true)
+
+	if err := client.CloseSend(); err != nil {
+		t.Fatalf("client.CloseSend() error = %v", err)
+	}
+
+	// the peer hasn't read the EOS yet, so it can still reply; the client
+	// closed only its send half and must still be able to Recv it.
+	want := "still-coming"
+	var discardedEOS string
+	if err := server.Recv(&discardedEOS); err != io.EOF {
+		t.Fatalf("server.Recv() of the EOS error = %v, want io.EOF", err)
+	}
+	if err := server.Send(want); err != nil {
+		t.Fatalf("server.Send() error = %v", err)
+	}
+
+	var got string
+	if err := client.Recv(&got); err != nil {
+		t.Fatalf("client.Recv() after CloseSend() error = %v, want nil", err)
+	}
+	if got != want {
+		t.Fatalf("client.Recv() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,142 @@
+/******************************************************
+# DESC    : application/octet-stream codec: the body is passed
+#			through untouched, the header is a length-prefixed
+#			json blob so Id/Target/Method/Error still round-trip.
+# AUTHOR  : Alex Stocks
+# VERSION : 1.0
+# LICENCE : Apache Licence 2.0
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2016-07-04 11:48
+# FILE    : bytes.go
+******************************************************/
+
+package bytes
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+import (
+	"github.com/golang/protobuf/proto"
+)
+
+import (
+	"github.com/AlexStocks/dubbogo/codec"
+)
+
+type header struct {
+	Id     uint64
+	Target string
+	Method string
+	Error  string
+	Header map[string]string
+}
+
+type bytesCodec struct {
+	conn io.ReadWriteCloser
+	r    *bufio.Reader
+}
+
+// NewCodec returns the application/octet-stream codec. Bodies must be
+// *[]byte or a proto.Message (Write also takes a []byte, and a nil body
+// as an empty one); anything else is rejected rather than silently
+// mangled.
+func NewCodec(rwc io.ReadWriteCloser) codec.Codec {
+	return &bytesCodec{conn: rwc, r: bufio.NewReader(rwc)}
+}
+
+func writeChunk(w io.Writer, b []byte) error {
+	if err := binary.Write(w, binary.BigEndian, uint32(len(b))); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+func readChunk(r io.Reader) ([]byte, error) {
+	var n uint32
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func (c *bytesCodec) Write(m *codec.Message, body interface{}) error {
+	hdr := header{Id: m.Id, Target: m.Target, Method: m.Method, Error: m.Error, Header: m.Header}
+	hb, err := json.Marshal(hdr)
+	if err != nil {
+		return err
+	}
+	if err := writeChunk(c.conn, hb); err != nil {
+		return err
+	}
+
+	var bb []byte
+	switch v := body.(type) {
+	case nil:
+	case *[]byte:
+		bb = *v
+	case []byte:
+		bb = v
+	case proto.Message:
+		if bb, err = proto.Marshal(v); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("bytes codec: unsupported body type %T, want *[]byte or proto.Message", body)
+	}
+	return writeChunk(c.conn, bb)
+}
+
+func (c *bytesCodec) ReadHeader(m *codec.Message, t codec.MessageType) error {
+	hb, err := readChunk(c.r)
+	if err != nil {
+		return err
+	}
+	var hdr header
+	if err := json.Unmarshal(hb, &hdr); err != nil {
+		return err
+	}
+	m.Id = hdr.Id
+	m.Target = hdr.Target
+	m.Method = hdr.Method
+	m.Error = hdr.Error
+	m.Header = hdr.Header
+	m.Type = t
+	return nil
+}
+
+func (c *bytesCodec) ReadBody(body interface{}) error {
+	bb, err := readChunk(c.r)
+	if err != nil {
+		return err
+	}
+	if body == nil {
+		return nil
+	}
+	switch v := body.(type) {
+	case *[]byte:
+		*v = bb
+		return nil
+	case proto.Message:
+		return proto.Unmarshal(bb, v)
+	default:
+		return fmt.Errorf("bytes codec: unsupported body type %T, want *[]byte or proto.Message", body)
+	}
+}
+
+func (c *bytesCodec) Close() error {
+	return c.conn.Close()
+}
+
+func (c *bytesCodec) String() string {
+	return "bytes"
+}
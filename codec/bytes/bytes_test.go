@@ -0,0 +1,52 @@
+package bytes
+
+import (
+	"bytes"
+	"testing"
+)
+
+import (
+	"github.com/AlexStocks/dubbogo/codec"
+)
+
+type nopReadWriteCloser struct {
+	*bytes.Buffer
+}
+
+func (nopReadWriteCloser) Close() error { return nil }
+
+func TestBytesCodecRoundTrip(t *testing.T) {
+	rwc := nopReadWriteCloser{Buffer: bytes.NewBuffer(nil)}
+	c := NewCodec(rwc)
+
+	payload := []byte("raw-octet-stream-body")
+	wm := &codec.Message{Id: 3, Target: "Echo", Method: "Echo.Call", Type: codec.Request}
+	if err := c.Write(wm, &payload); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var rm codec.Message
+	if err := c.ReadHeader(&rm, codec.Request); err != nil {
+		t.Fatalf("ReadHeader() error = %v", err)
+	}
+	if rm.Id != wm.Id || rm.Method != wm.Method {
+		t.Fatalf("ReadHeader() = %+v, want %+v", rm, wm)
+	}
+
+	var out []byte
+	if err := c.ReadBody(&out); err != nil {
+		t.Fatalf("ReadBody() error = %v", err)
+	}
+	if !bytes.Equal(out, payload) {
+		t.Fatalf("ReadBody() = %q, want %q", out, payload)
+	}
+}
+
+func TestBytesCodecRejectsUnsupportedBody(t *testing.T) {
+	rwc := nopReadWriteCloser{Buffer: bytes.NewBuffer(nil)}
+	c := NewCodec(rwc)
+
+	if err := c.Write(&codec.Message{Type: codec.Request}, 42); err == nil {
+		t.Fatal("Write() with unsupported body type should error")
+	}
+}
@@ -0,0 +1,165 @@
+/******************************************************
+# DESC    : grpc wire codec: 1 byte compressed-flag + 4 byte
+#			big-endian length + payload, for the
+#			application/grpc, application/grpc+proto and
+#			application/grpc+json content types.
+# AUTHOR  : Alex Stocks
+# VERSION : 1.0
+# LICENCE : Apache Licence 2.0
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2016-07-04 11:35
+# FILE    : grpc.go
+******************************************************/
+
+package grpc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+import (
+	"github.com/golang/protobuf/proto"
+)
+
+import (
+	"github.com/AlexStocks/dubbogo/codec"
+)
+
+type header struct {
+	Id     uint64
+	Target string
+	Method string
+	Error  string
+	Header map[string]string
+}
+
+type grpcCodec struct {
+	conn io.ReadWriteCloser
+	r    *bufio.Reader
+
+	// marshalJSON is true for application/grpc+json, false (protobuf)
+	// for application/grpc and application/grpc+proto.
+	marshalJSON bool
+}
+
+// NewCodec returns the application/grpc and application/grpc+proto codec.
+func NewCodec(rwc io.ReadWriteCloser) codec.Codec {
+	return &grpcCodec{conn: rwc, r: bufio.NewReader(rwc)}
+}
+
+// NewJSONCodec returns the application/grpc+json codec.
+func NewJSONCodec(rwc io.ReadWriteCloser) codec.Codec {
+	return &grpcCodec{conn: rwc, r: bufio.NewReader(rwc), marshalJSON: true}
+}
+
+func writeFrame(w io.Writer, compressed bool, b []byte) error {
+	var flag byte
+	if compressed {
+		flag = 1
+	}
+	if _, err := w.Write([]byte{flag}); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, uint32(len(b))); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+func readFrame(r io.Reader) ([]byte, error) {
+	hdr := make([]byte, 5)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(hdr[1:])
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func (c *grpcCodec) Write(m *codec.Message, body interface{}) error {
+	hdr := header{Id: m.Id, Target: m.Target, Method: m.Method, Error: m.Error, Header: m.Header}
+	hb, err := json.Marshal(hdr)
+	if err != nil {
+		return err
+	}
+	if err := writeFrame(c.conn, false, hb); err != nil {
+		return err
+	}
+
+	var bb []byte
+	switch v := body.(type) {
+	case nil:
+	case proto.Message:
+		if c.marshalJSON {
+			bb, err = json.Marshal(v)
+		} else {
+			bb, err = proto.Marshal(v)
+		}
+		if err != nil {
+			return err
+		}
+	case *[]byte:
+		bb = *v
+	case []byte:
+		bb = v
+	default:
+		return fmt.Errorf("grpc codec: unsupported body type %T, want proto.Message or *[]byte", body)
+	}
+	return writeFrame(c.conn, false, bb)
+}
+
+func (c *grpcCodec) ReadHeader(m *codec.Message, t codec.MessageType) error {
+	hb, err := readFrame(c.r)
+	if err != nil {
+		return err
+	}
+	var hdr header
+	if err := json.Unmarshal(hb, &hdr); err != nil {
+		return err
+	}
+	m.Id = hdr.Id
+	m.Target = hdr.Target
+	m.Method = hdr.Method
+	m.Error = hdr.Error
+	m.Header = hdr.Header
+	m.Type = t
+	return nil
+}
+
+func (c *grpcCodec) ReadBody(body interface{}) error {
+	bb, err := readFrame(c.r)
+	if err != nil {
+		return err
+	}
+	if body == nil {
+		return nil
+	}
+	switch v := body.(type) {
+	case proto.Message:
+		if c.marshalJSON {
+			return json.Unmarshal(bb, v)
+		}
+		return proto.Unmarshal(bb, v)
+	case *[]byte:
+		*v = bb
+		return nil
+	default:
+		return fmt.Errorf("grpc codec: unsupported body type %T, want proto.Message or *[]byte", body)
+	}
+}
+
+func (c *grpcCodec) Close() error {
+	return c.conn.Close()
+}
+
+func (c *grpcCodec) String() string {
+	return "grpc"
+}
@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"bytes"
+	"testing"
+)
+
+import (
+	"github.com/golang/protobuf/ptypes/wrappers"
+)
+
+import (
+	"github.com/AlexStocks/dubbogo/codec"
+)
+
+type nopReadWriteCloser struct {
+	*bytes.Buffer
+}
+
+func (nopReadWriteCloser) Close() error { return nil }
+
+func TestGrpcCodecProtoRoundTrip(t *testing.T) {
+	rwc := nopReadWriteCloser{Buffer: bytes.NewBuffer(nil)}
+	c := NewCodec(rwc)
+
+	body := &wrappers.StringValue{Value: "ping"}
+	wm := &codec.Message{Id: 7, Target: "Greeter", Method: "Greeter.Hello", Type: codec.Request}
+	if err := c.Write(wm, body); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var rm codec.Message
+	if err := c.ReadHeader(&rm, codec.Request); err != nil {
+		t.Fatalf("ReadHeader() error = %v", err)
+	}
+	if rm.Id != wm.Id || rm.Method != wm.Method {
+		t.Fatalf("ReadHeader() = %+v, want %+v", rm, wm)
+	}
+
+	var out wrappers.StringValue
+	if err := c.ReadBody(&out); err != nil {
+		t.Fatalf("ReadBody() error = %v", err)
+	}
+	if out.Value != body.Value {
+		t.Fatalf("ReadBody() = %q, want %q", out.Value, body.Value)
+	}
+}
+
+func TestGrpcCodecJSONRoundTrip(t *testing.T) {
+	rwc := nopReadWriteCloser{Buffer: bytes.NewBuffer(nil)}
+	c := NewJSONCodec(rwc)
+
+	body := &wrappers.StringValue{Value: "ping-json"}
+	if err := c.Write(&codec.Message{Type: codec.Request}, body); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var rm codec.Message
+	if err := c.ReadHeader(&rm, codec.Request); err != nil {
+		t.Fatalf("ReadHeader() error = %v", err)
+	}
+
+	var out wrappers.StringValue
+	if err := c.ReadBody(&out); err != nil {
+		t.Fatalf("ReadBody() error = %v", err)
+	}
+	if out.Value != body.Value {
+		t.Fatalf("ReadBody() = %q, want %q", out.Value, body.Value)
+	}
+}
+
+func TestGrpcFrameHas5ByteHeader(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	payload := []byte("hello")
+	if err := writeFrame(buf, false, payload); err != nil {
+		t.Fatalf("writeFrame() error = %v", err)
+	}
+	if buf.Len() != 5+len(payload) {
+		t.Fatalf("frame length = %d, want %d", buf.Len(), 5+len(payload))
+	}
+	if buf.Bytes()[0] != 0 {
+		t.Fatalf("compressed flag = %d, want 0", buf.Bytes()[0])
+	}
+}
@@ -0,0 +1,152 @@
+/******************************************************
+# DESC    : protobuf codec. wire format is a length-prefixed
+#			json header (Id, Target, Method, Error and Header
+#			of codec.Message; Type is not sent) followed by
+#			a length-prefixed protobuf-encoded body.
+# AUTHOR  : Alex Stocks
+# VERSION : 1.0
+# LICENCE : Apache Licence 2.0
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2016-07-04 11:02
+# FILE    : proto.go
+******************************************************/
+
+package proto
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+import (
+	"github.com/golang/protobuf/proto"
+)
+
+import (
+	"github.com/AlexStocks/dubbogo/codec"
+)
+
+type header struct {
+	Id     uint64
+	Target string
+	Method string
+	Error  string
+	Header map[string]string
+}
+
+type protoCodec struct {
+	conn io.ReadWriteCloser
+	r    *bufio.Reader
+}
+
+// NewCodec wraps rwc into a codec.Codec that frames its header and its
+// body as independent, length-prefixed blobs: the header is JSON, the
+// body is protobuf when it implements proto.Message and raw bytes when it
+// is a *[]byte or []byte; any other body type is rejected.
+func NewCodec(rwc io.ReadWriteCloser) codec.Codec {
+	return &protoCodec{
+		conn: rwc,
+		r:    bufio.NewReader(rwc),
+	}
+}
+
+func writeChunk(w io.Writer, b []byte) error {
+	if err := binary.Write(w, binary.BigEndian, uint32(len(b))); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+func readChunk(r io.Reader) ([]byte, error) {
+	var n uint32
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func (c *protoCodec) Write(m *codec.Message, body interface{}) error {
+	hdr := header{
+		Id:     m.Id,
+		Target: m.Target,
+		Method: m.Method,
+		Error:  m.Error,
+		Header: m.Header,
+	}
+	hb, err := json.Marshal(hdr)
+	if err != nil {
+		return err
+	}
+	if err := writeChunk(c.conn, hb); err != nil {
+		return err
+	}
+
+	var bb []byte
+	switch pb := body.(type) {
+	case nil:
+	case proto.Message:
+		if bb, err = proto.Marshal(pb); err != nil {
+			return err
+		}
+	case *[]byte:
+		bb = *pb
+	case []byte:
+		bb = pb
+	default:
+		return fmt.Errorf("proto codec: unsupported body type %T, want proto.Message or *[]byte", body)
+	}
+	return writeChunk(c.conn, bb)
+}
+
+func (c *protoCodec) ReadHeader(m *codec.Message, t codec.MessageType) error {
+	hb, err := readChunk(c.r)
+	if err != nil {
+		return err
+	}
+	var hdr header
+	if err := json.Unmarshal(hb, &hdr); err != nil {
+		return err
+	}
+	m.Id = hdr.Id
+	m.Target = hdr.Target
+	m.Method = hdr.Method
+	m.Error = hdr.Error
+	m.Header = hdr.Header
+	m.Type = t
+	return nil
+}
+
+func (c *protoCodec) ReadBody(body interface{}) error {
+	bb, err := readChunk(c.r)
+	if err != nil {
+		return err
+	}
+	if body == nil {
+		return nil
+	}
+	switch pb := body.(type) {
+	case proto.Message:
+		return proto.Unmarshal(bb, pb)
+	case *[]byte:
+		*pb = bb
+		return nil
+	default:
+		return fmt.Errorf("proto codec: unsupported body type %T, want proto.Message or *[]byte", body)
+	}
+}
+
+func (c *protoCodec) Close() error {
+	return c.conn.Close()
+}
+
+func (c *protoCodec) String() string {
+	return "proto"
+}
@@ -0,0 +1,79 @@
+package proto
+
+import (
+	"bytes"
+	"testing"
+)
+
+import (
+	"github.com/golang/protobuf/ptypes/wrappers"
+)
+
+import (
+	"github.com/AlexStocks/dubbogo/codec"
+)
+
+type nopReadWriteCloser struct {
+	*bytes.Buffer
+}
+
+func (nopReadWriteCloser) Close() error { return nil }
+
+func TestProtoCodecRoundTrip(t *testing.T) {
+	rwc := nopReadWriteCloser{Buffer: bytes.NewBuffer(nil)}
+	c := NewCodec(rwc)
+
+	reqBody := &wrappers.StringValue{Value: "ping"}
+	wm := &codec.Message{
+		Id:     1,
+		Target: "Greeter",
+		Method: "Greeter.Hello",
+		Type:   codec.Request,
+		Header: map[string]string{"x-test": "1"},
+	}
+	if err := c.Write(wm, reqBody); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var rm codec.Message
+	if err := c.ReadHeader(&rm, codec.Request); err != nil {
+		t.Fatalf("ReadHeader() error = %v", err)
+	}
+	if rm.Id != wm.Id || rm.Target != wm.Target || rm.Method != wm.Method {
+		t.Fatalf("ReadHeader() = %+v, want %+v", rm, wm)
+	}
+	if rm.Header["x-test"] != "1" {
+		t.Fatalf("ReadHeader() header = %v, want x-test=1", rm.Header)
+	}
+
+	var respBody wrappers.StringValue
+	if err := c.ReadBody(&respBody); err != nil {
+		t.Fatalf("ReadBody() error = %v", err)
+	}
+	if respBody.Value != reqBody.Value {
+		t.Fatalf("ReadBody() = %q, want %q", respBody.Value, reqBody.Value)
+	}
+}
+
+func TestProtoCodecRawBytesBody(t *testing.T) {
+	rwc := nopReadWriteCloser{Buffer: bytes.NewBuffer(nil)}
+	c := NewCodec(rwc)
+
+	payload := []byte("raw-bytes-body")
+	if err := c.Write(&codec.Message{Type: codec.Request}, &payload); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var m codec.Message
+	if err := c.ReadHeader(&m, codec.Request); err != nil {
+		t.Fatalf("ReadHeader() error = %v", err)
+	}
+
+	var out []byte
+	if err := c.ReadBody(&out); err != nil {
+		t.Fatalf("ReadBody() error = %v", err)
+	}
+	if !bytes.Equal(out, payload) {
+		t.Fatalf("ReadBody() = %q, want %q", out, payload)
+	}
+}
@@ -0,0 +1,30 @@
+/******************************************************
+# DESC    : proto-rpc codec; the RPC-envelope flavour of codec/proto,
+#			kept as its own package so its wire format can evolve
+#			independently of the plain application/protobuf codec.
+# AUTHOR  : Alex Stocks
+# VERSION : 1.0
+# LICENCE : Apache Licence 2.0
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2016-07-04 11:18
+# FILE    : protorpc.go
+******************************************************/
+
+package protorpc
+
+import (
+	"io"
+)
+
+import (
+	"github.com/AlexStocks/dubbogo/codec"
+	"github.com/AlexStocks/dubbogo/codec/proto"
+)
+
+// NewCodec returns the application/proto-rpc codec. Today it shares the
+// same length-prefixed framing as codec/proto; it is split out so a
+// future change to the RPC envelope does not have to touch the plain
+// application/protobuf codec.
+func NewCodec(rwc io.ReadWriteCloser) codec.Codec {
+	return proto.NewCodec(rwc)
+}
@@ -0,0 +1,47 @@
+package protorpc
+
+import (
+	"bytes"
+	"testing"
+)
+
+import (
+	"github.com/golang/protobuf/ptypes/wrappers"
+)
+
+import (
+	"github.com/AlexStocks/dubbogo/codec"
+)
+
+type nopReadWriteCloser struct {
+	*bytes.Buffer
+}
+
+func (nopReadWriteCloser) Close() error { return nil }
+
+func TestProtoRpcCodecRoundTrip(t *testing.T) {
+	rwc := nopReadWriteCloser{Buffer: bytes.NewBuffer(nil)}
+	c := NewCodec(rwc)
+
+	body := &wrappers.StringValue{Value: "ping"}
+	wm := &codec.Message{Id: 1, Method: "Greeter.Hello", Type: codec.Request}
+	if err := c.Write(wm, body); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var rm codec.Message
+	if err := c.ReadHeader(&rm, codec.Request); err != nil {
+		t.Fatalf("ReadHeader() error = %v", err)
+	}
+	if rm.Method != wm.Method {
+		t.Fatalf("ReadHeader() = %+v, want %+v", rm, wm)
+	}
+
+	var out wrappers.StringValue
+	if err := c.ReadBody(&out); err != nil {
+		t.Fatalf("ReadBody() error = %v", err)
+	}
+	if out.Value != body.Value {
+		t.Fatalf("ReadBody() = %q, want %q", out.Value, body.Value)
+	}
+}
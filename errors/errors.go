@@ -0,0 +1,108 @@
+/******************************************************
+# DESC    : structured rpc error carrying an HTTP-style status code
+#			alongside the originating service id and a free-form detail
+#			message, so callers can switch on Code instead of
+#			string-matching the error text
+# AUTHOR  : Alex Stocks
+# VERSION : 1.0
+# LICENCE : Apache Licence 2.0
+# EMAIL   : alexstocks@foxmail.com
+# MOD     : 2016-07-18 14:02
+# FILE    : errors.go
+******************************************************/
+
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Error is a structured rpc error. It marshals to and from JSON so it can
+// be carried across the wire as the plain-string codec.Message.Error field
+// and reconstructed with Parse on the receiving side.
+type Error struct {
+	Id     string `json:"id"`
+	Code   string `json:"code"`
+	Detail string `json:"detail"`
+	Status string `json:"status"`
+}
+
+func (e *Error) Error() string {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return e.Detail
+	}
+	return string(b)
+}
+
+// Parse recovers an *Error from a JSON-encoded string, falling back to
+// {Detail: s} when s is not a JSON-encoded Error (e.g. a bare error string
+// from a peer that doesn't speak this package).
+func Parse(s string) *Error {
+	e := new(Error)
+	if err := json.Unmarshal([]byte(s), e); err != nil {
+		e.Detail = s
+	}
+	return e
+}
+
+// BadRequest generates a 400 error.
+func BadRequest(id, format string, a ...interface{}) error {
+	return &Error{
+		Id:     id,
+		Code:   "400",
+		Detail: fmt.Sprintf(format, a...),
+		Status: "400",
+	}
+}
+
+// Unauthorized generates a 401 error.
+func Unauthorized(id, format string, a ...interface{}) error {
+	return &Error{
+		Id:     id,
+		Code:   "401",
+		Detail: fmt.Sprintf(format, a...),
+		Status: "401",
+	}
+}
+
+// Forbidden generates a 403 error.
+func Forbidden(id, format string, a ...interface{}) error {
+	return &Error{
+		Id:     id,
+		Code:   "403",
+		Detail: fmt.Sprintf(format, a...),
+		Status: "403",
+	}
+}
+
+// NotFound generates a 404 error.
+func NotFound(id, format string, a ...interface{}) error {
+	return &Error{
+		Id:     id,
+		Code:   "404",
+		Detail: fmt.Sprintf(format, a...),
+		Status: "404",
+	}
+}
+
+// Timeout generates a 408 error.
+func Timeout(id, format string, a ...interface{}) error {
+	return &Error{
+		Id:     id,
+		Code:   "408",
+		Detail: fmt.Sprintf(format, a...),
+		Status: "408",
+	}
+}
+
+// InternalServerError generates a 500 error.
+func InternalServerError(id, format string, a ...interface{}) error {
+	return &Error{
+		Id:     id,
+		Code:   "500",
+		Detail: fmt.Sprintf(format, a...),
+		Status: "500",
+	}
+}
@@ -0,0 +1,53 @@
+package errors
+
+import "testing"
+
+func TestErrorJSONRoundTrip(t *testing.T) {
+	err := NotFound("go.dubbogo.client", "user %d not found", 7)
+	e, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("NotFound() = %T, want *Error", err)
+	}
+
+	parsed := Parse(e.Error())
+	if parsed.Id != e.Id || parsed.Code != e.Code || parsed.Detail != e.Detail {
+		t.Fatalf("Parse(e.Error()) = %+v, want %+v", parsed, e)
+	}
+	if parsed.Code != "404" {
+		t.Fatalf("Code = %q, want 404", parsed.Code)
+	}
+}
+
+func TestParseFallsBackToDetailForPlainString(t *testing.T) {
+	parsed := Parse("connection refused")
+	if parsed.Detail != "connection refused" {
+		t.Fatalf("Detail = %q, want %q", parsed.Detail, "connection refused")
+	}
+	if parsed.Code != "" {
+		t.Fatalf("Code = %q, want empty", parsed.Code)
+	}
+}
+
+func TestConstructors(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		code string
+	}{
+		{"BadRequest", BadRequest("id", "bad"), "400"},
+		{"Unauthorized", Unauthorized("id", "nope"), "401"},
+		{"Forbidden", Forbidden("id", "nope"), "403"},
+		{"NotFound", NotFound("id", "nope"), "404"},
+		{"Timeout", Timeout("id", "nope"), "408"},
+		{"InternalServerError", InternalServerError("id", "boom"), "500"},
+	}
+	for _, c := range cases {
+		e, ok := c.err.(*Error)
+		if !ok {
+			t.Fatalf("%s: got %T, want *Error", c.name, c.err)
+		}
+		if e.Code != c.code {
+			t.Fatalf("%s: Code = %q, want %q", c.name, e.Code, c.code)
+		}
+	}
+}